@@ -0,0 +1,64 @@
+package config
+
+import "reflect"
+
+// deepCopy returns a new pointer to a value of the same type as obj
+// (which must be a pointer), with its exported contents recursively
+// copied so the result shares no mutable state with obj. Unexported
+// struct fields are not copied and are left at their zero value.
+func deepCopy(obj interface{}) interface{} {
+	src := reflect.ValueOf(obj)
+	dst := reflect.New(src.Elem().Type())
+	cloneInto(dst.Elem(), src.Elem())
+	return dst.Interface()
+}
+
+func cloneInto(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).PkgPath != "" {
+				continue // unexported
+			}
+			cloneInto(dst.Field(i), src.Field(i))
+		}
+
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		cloneInto(dst.Elem(), src.Elem())
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		for _, k := range src.MapKeys() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			cloneInto(v, src.MapIndex(k))
+			dst.SetMapIndex(k, v)
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			cloneInto(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		cloneInto(elem, src.Elem())
+		dst.Set(elem)
+
+	default:
+		dst.Set(src)
+	}
+}
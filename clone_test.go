@@ -0,0 +1,47 @@
+package config
+
+import "testing"
+
+type cloneTestConfig struct {
+	Name string
+	Tags map[string]string
+	Sub  *struct {
+		Value int
+	}
+}
+
+func TestDeepCopyIsIndependent(t *testing.T) {
+	orig := &cloneTestConfig{
+		Name: "billing",
+		Tags: map[string]string{"env": "prod"},
+		Sub:  &struct{ Value int }{Value: 1},
+	}
+
+	copied := deepCopy(orig).(*cloneTestConfig)
+
+	copied.Name = "other"
+	copied.Tags["env"] = "staging"
+	copied.Sub.Value = 2
+
+	if orig.Name != "billing" {
+		t.Errorf("orig.Name = %q, want %q - mutating the copy must not affect the original", orig.Name, "billing")
+	}
+	if orig.Tags["env"] != "prod" {
+		t.Errorf("orig.Tags[env] = %q, want %q", orig.Tags["env"], "prod")
+	}
+	if orig.Sub.Value != 1 {
+		t.Errorf("orig.Sub.Value = %d, want %d", orig.Sub.Value, 1)
+	}
+}
+
+func TestDeepCopyNilFields(t *testing.T) {
+	orig := &cloneTestConfig{Name: "billing"}
+	copied := deepCopy(orig).(*cloneTestConfig)
+
+	if copied.Tags != nil {
+		t.Errorf("copied.Tags = %v, want nil", copied.Tags)
+	}
+	if copied.Sub != nil {
+		t.Errorf("copied.Sub = %v, want nil", copied.Sub)
+	}
+}
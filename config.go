@@ -1,32 +1,31 @@
 package config
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
-	"strconv"
 	"strings"
-
-	"github.com/fatih/camelcase"
 )
 
 var errWrongConfigurationType = errors.New("Configuration type must be a pointer to a struct")
 
-// LoadConfig reads configuration from path and stores it to obj interface
-// The format is deduced from the file extension
-//	* .json    - is decoded as json
-//	* .yml     - is decoded as yaml
+// LoadConfig reads configuration from path and stores it to obj interface.
+// The format is deduced from the file extension and decoded with the
+// decoder registered for it - see RegisterDecoder. Built in are .json,
+// .yaml/.yml, .toml and .env.
+//
+// Fields tagged `config:"NAME"` or `env:"NAME"` fall back to that
+// literal environment variable if the file left them unset.
 func LoadConfig(path string, obj interface{}) error {
-	err := checkConfigObj(obj)
-	if err != nil {
+	if err := checkConfigObj(obj); err != nil {
 		return err
 	}
 
-	_, err = os.Stat(path)
+	_, err := os.Stat(path)
 	if err != nil {
 		return err
 	}
@@ -36,64 +35,45 @@ func LoadConfig(path string, obj interface{}) error {
 		return err
 	}
 
-	switch filepath.Ext(path) {
-	case ".json":
-		err := json.Unmarshal(data, obj)
-		if err != nil {
-			return err
-		}
+	if err := LoadConfigFromReader(bytes.NewReader(data), filepath.Ext(path), obj); err != nil {
+		return err
 	}
 
-	return nil
+	// Fields tagged with an explicit env/config name fall back to that
+	// variable if the file left them unset.
+	return applyTagEnv(reflect.ValueOf(obj).Elem(), nil, nil)
 }
 
-// SyncEnv overrides obj field's values that are set in the environment.
-//
-// The environment variable names are derived from config fields by underscoring, and uppercasing
-// the name. E.g. AppName will have a corresponding environment variable APP_NAME
-//
-// NOTE only int, string and bool fields are supported and the corresponding values are set.
-// when the field value is not supported it is ignored.
-func SyncEnv(obj interface{}) error {
-	err := checkConfigObj(obj)
+// LoadConfigFromReader decodes configuration read from r into obj, using
+// the decoder registered for format (an extension such as ".json" or
+// "json").
+func LoadConfigFromReader(r io.Reader, format string, obj interface{}) error {
+	if err := checkConfigObj(obj); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(format, ".") {
+		format = "." + format
+	}
+
+	decode, err := decoderFor(format)
 	if err != nil {
 		return err
 	}
 
-	cfg := reflect.ValueOf(obj).Elem()
-	cfgType := cfg.Type()
-
-	for k := range make([]struct{}, cfgType.NumField()) {
-		field := cfgType.Field(k)
-
-		cm := getEnvName(field.Name)
-		env := os.Getenv(cm)
-		if env == "" {
-			continue
-		}
-
-		switch field.Type.Kind() {
-		case reflect.String:
-			cfg.FieldByName(field.Name).SetString(env)
-		case reflect.Int:
-			v, err := strconv.Atoi(env)
-			if err != nil {
-				return fmt.Errorf(" Error loading config field %s %v", field.Name, err)
-			}
-			cfg.FieldByName(field.Name).Set(reflect.ValueOf(v))
-		case reflect.Bool:
-			b, err := strconv.ParseBool(env)
-			if err != nil {
-				return fmt.Errorf(" Error loading config field %s %v", field.Name, err)
-			}
-			cfg.FieldByName(field.Name).SetBool(b)
-		}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	return decode(data, obj)
 }
 
-// LoadAndSync reads configuration from path and stores it to obj interface
-// and syncs config with environment variables
+// LoadAndSync reads configuration from path, stores it to obj interface,
+// syncs config with environment variables, and finally applies any
+// default:"..." tags to fields both sources left at their zero value.
+// If a field tagged required:"true" is still zero after that, LoadAndSync
+// returns a MultiError listing every such field.
 func LoadAndSync(path string, obj interface{}) error {
 	err := LoadConfig(path, obj)
 	if err != nil {
@@ -105,25 +85,7 @@ func LoadAndSync(path string, obj interface{}) error {
 		return err
 	}
 
-	return nil
-}
-
-// getEnvName returns all upper case and underscore separated string, from field.
-// field is a camel case string.
-//
-// example
-//	AppName will change to APP_NAME
-func getEnvName(field string) string {
-	camSplit := camelcase.Split(field)
-	var rst string
-	for k, v := range camSplit {
-		if k == 0 {
-			rst = strings.ToUpper(v)
-			continue
-		}
-		rst = rst + "_" + strings.ToUpper(v)
-	}
-	return rst
+	return applyDefaultsAndRequired(obj)
 }
 
 func checkConfigObj(obj interface{}) error {
@@ -0,0 +1,35 @@
+// Package consul provides a config.Source that fetches configuration
+// from a single key in Consul's KV store. It lives in its own module so
+// that github.com/sedind/config itself doesn't pull in Consul's
+// dependency tree for callers who never use it - only importing this
+// package pays that cost.
+package consul
+
+import (
+	"context"
+	"fmt"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/sedind/config"
+)
+
+// Source fetches configuration from a single key in Consul's KV store.
+type Source struct {
+	Client *consul.Client
+	Key    string
+	Format string // decoder hint, e.g. "json" or "yaml"
+}
+
+var _ config.Source = (*Source)(nil)
+
+// Fetch implements config.Source.
+func (s *Source) Fetch(ctx context.Context) ([]byte, string, error) {
+	pair, _, err := s.Client.KV().Get(s.Key, (&consul.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, "", err
+	}
+	if pair == nil {
+		return nil, "", fmt.Errorf("config: consul key %q not found", s.Key)
+	}
+	return pair.Value, s.Format, nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/camelcase"
+)
+
+// EnvPrefix is the prefix every environment variable must carry to be
+// considered by SyncEnv. Variables without this prefix are ignored.
+var EnvPrefix = "CONFIG"
+
+// SyncEnv overrides obj's field values with values found in the process
+// environment.
+//
+// Rather than walking the struct looking for a matching variable, SyncEnv
+// walks the environment and resolves each variable into the struct: for
+// every variable named "<EnvPrefix>_...", the remainder of the name is
+// split on "_" and the parts are matched, case insensitively, against the
+// camelcase-split name of a struct field, so APP_NAME resolves to a field
+// named AppName. Matching descends through nested structs, pointers and
+// map[string]... fields - creating map keys and allocating pointer and
+// interface{} targets as needed - until the path is exhausted at a leaf
+// field, whose value is decoded from the variable's string value.
+//
+// Because "_" is the path separator, struct field names must not contain
+// an underscore.
+//
+// A field tagged with an explicit name, e.g. `env:"DATABASE_URL"` or
+// `config:"DATABASE_URL"`, falls back to that literal variable name if it
+// is still unset once the derived path above has been applied.
+func SyncEnv(obj interface{}) error {
+	_, err := syncEnvWithPrefix(obj, EnvPrefix)
+	return err
+}
+
+// syncEnvWithPrefix is SyncEnv with the prefix passed explicitly rather
+// than read from the EnvPrefix package variable. It also returns a
+// fieldMask of every field a variable actually resolved into.
+func syncEnvWithPrefix(obj interface{}, prefix string) (fieldMask, error) {
+	if err := checkConfigObj(obj); err != nil {
+		return nil, err
+	}
+
+	p := strings.ToUpper(prefix) + "_"
+	cfg := reflect.ValueOf(obj).Elem()
+	mask := newFieldMask()
+
+	for _, kv := range os.Environ() {
+		idx := strings.Index(kv, "=")
+		if idx < 0 {
+			continue
+		}
+		name, value := kv[:idx], kv[idx+1:]
+
+		if !strings.HasPrefix(strings.ToUpper(name), p) {
+			continue
+		}
+
+		path := strings.Split(strings.ToUpper(name[len(p):]), "_")
+		if len(path) == 0 || path[0] == "" {
+			continue
+		}
+
+		if err := resolveEnvPath(cfg, path, value, mask, nil); err != nil {
+			return nil, fmt.Errorf("config: env %s: %w", name, err)
+		}
+	}
+
+	if err := applyTagEnv(cfg, mask, nil); err != nil {
+		return nil, err
+	}
+
+	return mask, nil
+}
+
+// resolveEnvPath descends v along path, allocating pointers and map keys
+// as it goes, and decodes value into whatever field the path ends on.
+// trail is the dotted Go field-name path walked so far; whenever a leaf
+// is reached, it is recorded in mask (which may be nil if the caller
+// doesn't need one).
+func resolveEnvPath(v reflect.Value, path []string, value string, mask fieldMask, trail []string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return fmt.Errorf("cannot allocate nil %s", v.Type())
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return resolveStructPath(v, path, value, mask, trail)
+	case reflect.Map:
+		return resolveMapPath(v, path, value, mask, trail)
+	case reflect.Interface:
+		return resolveInterfacePath(v, path, value, mask, trail)
+	default:
+		if len(path) != 0 {
+			return fmt.Errorf("field of type %s cannot be traversed further, remaining path %q", v.Type(), strings.Join(path, "_"))
+		}
+		if err := decodeValue(value, v); err != nil {
+			return err
+		}
+		mask.addAncestors(trail)
+		return nil
+	}
+}
+
+func resolveStructPath(v reflect.Value, path []string, value string, mask fieldMask, trail []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path does not reach a leaf field on %s", v.Type())
+	}
+
+	t := v.Type()
+
+	type candidate struct {
+		index int
+		n     int
+	}
+	var candidates []candidate
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		n, ok := matchFieldPath(field.Name, path)
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, candidate{index: i, n: n})
+	}
+
+	// Try the most specific (longest) match first, and fall back to
+	// shorter matches if a longer one can't resolve the remainder, so
+	// a field like AppName wins over App for CONFIG_APP_NAME regardless
+	// of declaration order.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].n > candidates[j].n
+	})
+
+	var lastErr error
+	for _, c := range candidates {
+		field := t.Field(c.index)
+		if err := resolveEnvPath(v.Field(c.index), path[c.n:], value, mask, appendTrail(trail, field.Name)); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+
+	if lastErr != nil {
+		return lastErr
+	}
+
+	return fmt.Errorf("no field of %s matches %q", t, strings.Join(path, "_"))
+}
+
+// matchFieldPath reports whether the leading segments of path match
+// name's camelcase-split parts, and if so how many segments were
+// consumed. APP_NAME matches field AppName (parts "App", "Name").
+func matchFieldPath(name string, path []string) (int, bool) {
+	parts := camelcase.Split(name)
+	if len(path) < len(parts) {
+		return 0, false
+	}
+	for i, part := range parts {
+		if !strings.EqualFold(path[i], part) {
+			return 0, false
+		}
+	}
+	return len(parts), true
+}
+
+func resolveMapPath(v reflect.Value, path []string, value string, mask fieldMask, trail []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path does not reach a leaf field on %s", v.Type())
+	}
+
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("map field %s has non-string key type %s, which SyncEnv cannot resolve", v.Type(), v.Type().Key())
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	keyStr := strings.ToLower(path[0])
+	key := reflect.ValueOf(keyStr).Convert(v.Type().Key())
+	elem := reflect.New(v.Type().Elem()).Elem()
+	if existing := v.MapIndex(key); existing.IsValid() {
+		elem.Set(existing)
+	}
+
+	if err := resolveEnvPath(elem, path[1:], value, mask, appendTrail(trail, keyStr)); err != nil {
+		return err
+	}
+
+	v.SetMapIndex(key, elem)
+	return nil
+}
+
+func resolveInterfacePath(v reflect.Value, path []string, value string, mask fieldMask, trail []string) error {
+	if len(path) == 0 {
+		v.Set(reflect.ValueOf(value))
+		mask.addAncestors(trail)
+		return nil
+	}
+
+	m := map[string]interface{}{}
+	if !v.IsNil() {
+		if existing, ok := v.Interface().(map[string]interface{}); ok {
+			m = existing
+		}
+	}
+
+	mv := reflect.ValueOf(m)
+	if err := resolveMapPath(mv, path, value, mask, trail); err != nil {
+		return err
+	}
+
+	v.Set(mv)
+	return nil
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+type envTestConfig struct {
+	AppName string
+	Port    int
+	Debug   bool
+	Timeout time.Duration
+	Nested  struct {
+		MaxRetries int
+	}
+	Tags  map[string]string
+	Extra interface{}
+}
+
+// setEnv sets kv in the process environment and returns a func that
+// unsets it again, for the caller to defer.
+func setEnv(t *testing.T, kv map[string]string) func() {
+	t.Helper()
+	for k, v := range kv {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("os.Setenv(%q): %v", k, err)
+		}
+	}
+	return func() {
+		for k := range kv {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestSyncEnv(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		"CONFIG_APP_NAME":           "billing",
+		"CONFIG_PORT":               "8080",
+		"CONFIG_DEBUG":              "true",
+		"CONFIG_TIMEOUT":            "5s",
+		"CONFIG_NESTED_MAX_RETRIES": "3",
+		"CONFIG_TAGS_ENV":           "prod",
+	})()
+
+	var cfg envTestConfig
+	if err := SyncEnv(&cfg); err != nil {
+		t.Fatalf("SyncEnv: %v", err)
+	}
+
+	if cfg.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "billing")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want %d", cfg.Port, 8080)
+	}
+	if !cfg.Debug {
+		t.Errorf("Debug = false, want true")
+	}
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want %v", cfg.Timeout, 5*time.Second)
+	}
+	if cfg.Nested.MaxRetries != 3 {
+		t.Errorf("Nested.MaxRetries = %d, want %d", cfg.Nested.MaxRetries, 3)
+	}
+	if cfg.Tags["env"] != "prod" {
+		t.Errorf("Tags[env] = %q, want %q", cfg.Tags["env"], "prod")
+	}
+}
+
+func TestSyncEnvIgnoresUnprefixed(t *testing.T) {
+	defer setEnv(t, map[string]string{"APP_NAME": "billing"})()
+
+	var cfg envTestConfig
+	if err := SyncEnv(&cfg); err != nil {
+		t.Fatalf("SyncEnv: %v", err)
+	}
+	if cfg.AppName != "" {
+		t.Errorf("AppName = %q, want empty - unprefixed vars must be ignored", cfg.AppName)
+	}
+}
+
+func TestSyncEnvNoMatchingField(t *testing.T) {
+	defer setEnv(t, map[string]string{"CONFIG_DOES_NOT_EXIST": "x"})()
+
+	var cfg envTestConfig
+	if err := SyncEnv(&cfg); err == nil {
+		t.Fatal("SyncEnv: want error for unmatched path, got nil")
+	}
+}
+
+func TestSyncEnvWithPrefixIsolated(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		"CONFIG_APP_NAME": "default-prefix",
+		"PROD_APP_NAME":   "prod-prefix",
+	})()
+
+	var cfg envTestConfig
+	mask, err := syncEnvWithPrefix(&cfg, "PROD")
+	if err != nil {
+		t.Fatalf("syncEnvWithPrefix: %v", err)
+	}
+	if cfg.AppName != "prod-prefix" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "prod-prefix")
+	}
+	if !mask.has([]string{"AppName"}) {
+		t.Errorf("mask does not contain AppName")
+	}
+}
+
+func TestSyncEnvPrefersLongestFieldMatch(t *testing.T) {
+	type cfg struct {
+		App     string
+		AppName string
+	}
+	defer setEnv(t, map[string]string{"CONFIG_APP_NAME": "billing"})()
+
+	var c cfg
+	if err := SyncEnv(&c); err != nil {
+		t.Fatalf("SyncEnv: %v", err)
+	}
+	if c.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", c.AppName, "billing")
+	}
+	if c.App != "" {
+		t.Errorf("App = %q, want empty - CONFIG_APP_NAME must not misroute to the shorter prefix field", c.App)
+	}
+}
+
+func TestSyncEnvPrefersLongestMatchOverNestedField(t *testing.T) {
+	type cfg struct {
+		App struct {
+			Name string
+		}
+		AppName string
+	}
+	defer setEnv(t, map[string]string{"CONFIG_APP_NAME": "billing"})()
+
+	var c cfg
+	if err := SyncEnv(&c); err != nil {
+		t.Fatalf("SyncEnv: %v", err)
+	}
+	if c.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", c.AppName, "billing")
+	}
+	if c.App.Name != "" {
+		t.Errorf("App.Name = %q, want empty - CONFIG_APP_NAME must not silently misroute into App.Name", c.App.Name)
+	}
+}
+
+func TestSyncEnvNonStringMapKeyErrors(t *testing.T) {
+	type cfg struct {
+		M map[int]string
+	}
+	defer setEnv(t, map[string]string{"CONFIG_M_5": "hello"})()
+
+	var c cfg
+	if err := SyncEnv(&c); err == nil {
+		t.Fatal("SyncEnv: want error for map with non-string key, got nil")
+	}
+}
+
+func TestMatchFieldPath(t *testing.T) {
+	cases := []struct {
+		name string
+		path []string
+		n    int
+		ok   bool
+	}{
+		{"AppName", []string{"APP", "NAME", "EXTRA"}, 2, true},
+		{"AppName", []string{"app", "name"}, 2, true},
+		{"Port", []string{"PORT"}, 1, true},
+		{"Port", []string{"APP", "NAME"}, 0, false},
+	}
+	for _, c := range cases {
+		n, ok := matchFieldPath(c.name, c.path)
+		if n != c.n || ok != c.ok {
+			t.Errorf("matchFieldPath(%q, %v) = (%d, %v), want (%d, %v)", c.name, c.path, n, ok, c.n, c.ok)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import "strings"
+
+// MultiError collects several configuration errors - for example every
+// field tagged required:"true" that is still unset after all sources
+// have been applied - so callers can report them all at once instead of
+// failing on the first.
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (m *MultiError) append(err error) {
+	*m = append(*m, err)
+}
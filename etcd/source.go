@@ -0,0 +1,35 @@
+// Package etcd provides a config.Source that fetches configuration from
+// a single key in etcd. It lives in its own module so that
+// github.com/sedind/config itself doesn't pull in etcd's dependency
+// tree for callers who never use it - only importing this package pays
+// that cost.
+package etcd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sedind/config"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Source fetches configuration from a single key in etcd.
+type Source struct {
+	Client *clientv3.Client
+	Key    string
+	Format string // decoder hint, e.g. "json" or "yaml"
+}
+
+var _ config.Source = (*Source)(nil)
+
+// Fetch implements config.Source.
+func (s *Source) Fetch(ctx context.Context) ([]byte, string, error) {
+	resp, err := s.Client.Get(ctx, s.Key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, "", fmt.Errorf("config: etcd key %q not found", s.Key)
+	}
+	return resp.Kvs[0].Value, s.Format, nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// DecoderFunc decodes data into obj. It is the shape every format
+// decoder - built-in or registered via RegisterDecoder - must implement.
+type DecoderFunc func(data []byte, obj interface{}) error
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]DecoderFunc{
+		".json": jsonDecode,
+		".yaml": yamlDecode,
+		".yml":  yamlDecode,
+		".toml": tomlDecode,
+		".env":  dotenvDecode,
+	}
+)
+
+// RegisterDecoder registers fn as the decoder for files with the given
+// extension (including the leading dot, e.g. ".hcl"). Registering a
+// decoder for an extension that already has one, including the built-in
+// formats, replaces it.
+func RegisterDecoder(ext string, fn DecoderFunc) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(ext)] = fn
+}
+
+func decoderFor(ext string) (DecoderFunc, error) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	fn, ok := decoders[strings.ToLower(ext)]
+	if !ok {
+		return nil, fmt.Errorf("config: no decoder registered for format %q", ext)
+	}
+	return fn, nil
+}
+
+func jsonDecode(data []byte, obj interface{}) error {
+	return json.Unmarshal(data, obj)
+}
+
+func yamlDecode(data []byte, obj interface{}) error {
+	return yaml.Unmarshal(data, obj)
+}
+
+func tomlDecode(data []byte, obj interface{}) error {
+	return toml.Unmarshal(data, obj)
+}
+
+// genericDecoders mirrors the built-in .json, .yaml/.yml and .toml
+// entries of decoders, but targets a generic map instead of a typed
+// struct. Loader.Load uses it to find out which keys a file actually
+// contained, for merge masking - see buildFileMask in loader.go. A .env
+// file has no entry here; its mask comes from dotenvDecodeMasked.
+var genericDecoders = map[string]func([]byte) (interface{}, error){
+	".json": func(data []byte) (interface{}, error) {
+		var v interface{}
+		err := json.Unmarshal(data, &v)
+		return v, err
+	},
+	".yaml": func(data []byte) (interface{}, error) {
+		var v interface{}
+		err := yaml.Unmarshal(data, &v)
+		return v, err
+	},
+	".yml": func(data []byte) (interface{}, error) {
+		var v interface{}
+		err := yaml.Unmarshal(data, &v)
+		return v, err
+	},
+	".toml": func(data []byte) (interface{}, error) {
+		var v map[string]interface{}
+		err := toml.Unmarshal(data, &v)
+		return v, err
+	},
+}
+
+// dotenvDecode treats data as dotenv-style KEY=VALUE lines and resolves
+// each CONFIG_-prefixed key into obj via the same path-resolution SyncEnv
+// uses, plus any env/config-tagged field by its literal key. It never
+// touches the process environment - only keys actually present in data
+// are considered, so decoding a .env file can never pull in an unrelated
+// value from the host environment.
+func dotenvDecode(data []byte, obj interface{}) error {
+	_, err := dotenvDecodeMasked(data, obj)
+	return err
+}
+
+// dotenvDecodeMasked is dotenvDecode plus the fieldMask of every field a
+// line actually resolved into, for callers (Loader.Load) that need to
+// tell that apart from a field left at its zero value.
+func dotenvDecodeMasked(data []byte, obj interface{}) (fieldMask, error) {
+	if err := checkConfigObj(obj); err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ToUpper(EnvPrefix) + "_"
+	cfg := reflect.ValueOf(obj).Elem()
+	mask := newFieldMask()
+	env := map[string]string{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			continue
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		env[key] = value
+
+		if !strings.HasPrefix(strings.ToUpper(key), prefix) {
+			continue
+		}
+
+		path := strings.Split(strings.ToUpper(key[len(prefix):]), "_")
+		if len(path) == 0 || path[0] == "" {
+			continue
+		}
+
+		if err := resolveEnvPath(cfg, path, value, mask, nil); err != nil {
+			return nil, fmt.Errorf("config: dotenv %s: %w", key, err)
+		}
+	}
+
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	if err := applyTagEnvFrom(cfg, lookup, mask, nil); err != nil {
+		return nil, err
+	}
+
+	return mask, nil
+}
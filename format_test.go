@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+type formatTestConfig struct {
+	AppName string
+	Port    int
+}
+
+func TestDecoderForBuiltins(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml", ".yml", ".toml", ".env"} {
+		if _, err := decoderFor(ext); err != nil {
+			t.Errorf("decoderFor(%q): %v", ext, err)
+		}
+	}
+}
+
+func TestDecoderForUnknown(t *testing.T) {
+	if _, err := decoderFor(".hcl"); err == nil {
+		t.Fatal("decoderFor(\".hcl\"): want error, got nil")
+	}
+}
+
+func TestRegisterDecoderOverridesBuiltin(t *testing.T) {
+	called := false
+	RegisterDecoder(".json", func(data []byte, obj interface{}) error {
+		called = true
+		return nil
+	})
+	defer RegisterDecoder(".json", jsonDecode)
+
+	var cfg formatTestConfig
+	if err := LoadConfigFromReader(strings.NewReader("{}"), ".json", &cfg); err != nil {
+		t.Fatalf("LoadConfigFromReader: %v", err)
+	}
+	if !called {
+		t.Error("registered decoder was not used")
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	var cfg formatTestConfig
+	if err := jsonDecode([]byte(`{"AppName":"billing","Port":8080}`), &cfg); err != nil {
+		t.Fatalf("jsonDecode: %v", err)
+	}
+	if cfg.AppName != "billing" || cfg.Port != 8080 {
+		t.Errorf("cfg = %+v, want {billing 8080}", cfg)
+	}
+}
+
+func TestYAMLDecode(t *testing.T) {
+	var cfg formatTestConfig
+	if err := yamlDecode([]byte("appname: billing\nport: 8080\n"), &cfg); err != nil {
+		t.Fatalf("yamlDecode: %v", err)
+	}
+	if cfg.AppName != "billing" || cfg.Port != 8080 {
+		t.Errorf("cfg = %+v, want {billing 8080}", cfg)
+	}
+}
+
+func TestDotenvDecodeDoesNotTouchProcessEnv(t *testing.T) {
+	const data = "CONFIG_APP_NAME=billing\nCONFIG_PORT=8080\n"
+
+	var cfg formatTestConfig
+	mask, err := dotenvDecodeMasked([]byte(data), &cfg)
+	if err != nil {
+		t.Fatalf("dotenvDecodeMasked: %v", err)
+	}
+	if cfg.AppName != "billing" || cfg.Port != 8080 {
+		t.Errorf("cfg = %+v, want {billing 8080}", cfg)
+	}
+	if !mask.has([]string{"AppName"}) || !mask.has([]string{"Port"}) {
+		t.Errorf("mask missing expected fields: %+v", mask)
+	}
+	if v, ok := os.LookupEnv("CONFIG_APP_NAME"); ok {
+		t.Errorf("dotenv decode leaked CONFIG_APP_NAME=%q into the process environment", v)
+	}
+}
+
+func TestDotenvDecodeTagFallbackIgnoresRealEnv(t *testing.T) {
+	type taggedConfig struct {
+		APIKey string `env:"API_KEY_REAL"`
+	}
+
+	os.Setenv("API_KEY_REAL", "leaked-from-host")
+	defer os.Unsetenv("API_KEY_REAL")
+
+	var cfg taggedConfig
+	if err := dotenvDecode([]byte("# no relevant keys here\n"), &cfg); err != nil {
+		t.Fatalf("dotenvDecode: %v", err)
+	}
+	if cfg.APIKey != "" {
+		t.Errorf("APIKey = %q, want empty - dotenv decode must not fall back to the real process environment", cfg.APIKey)
+	}
+}
+
+func TestDotenvDecodeTagFallbackResolvesFromFile(t *testing.T) {
+	type taggedConfig struct {
+		APIKey string `env:"API_KEY_REAL"`
+	}
+
+	var cfg taggedConfig
+	if err := dotenvDecode([]byte("API_KEY_REAL=from-file\n"), &cfg); err != nil {
+		t.Fatalf("dotenvDecode: %v", err)
+	}
+	if cfg.APIKey != "from-file" {
+		t.Errorf("APIKey = %q, want %q", cfg.APIKey, "from-file")
+	}
+}
+
+func TestDotenvDecodeIgnoresCommentsAndBlankLines(t *testing.T) {
+	const data = "# a comment\n\nCONFIG_APP_NAME=billing\n"
+
+	var cfg formatTestConfig
+	if err := dotenvDecode([]byte(data), &cfg); err != nil {
+		t.Fatalf("dotenvDecode: %v", err)
+	}
+	if cfg.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "billing")
+	}
+}
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// sourceKind identifies which branch of Loader.Load a source is handled by.
+type sourceKind int
+
+const (
+	sourceFile sourceKind = iota
+	sourceEnv
+	sourceFlags
+)
+
+type loaderSource struct {
+	kind      sourceKind
+	file      string
+	envPrefix string
+	flags     *flag.FlagSet
+}
+
+// Loader composes multiple configuration sources - files, environment
+// variables, command-line flags - applying them in the order they were
+// added. A source added later overrides an earlier one field by field,
+// not by replacing the whole struct.
+type Loader struct {
+	sources []loaderSource
+}
+
+// NewLoader returns an empty Loader ready to have sources added to it.
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// AddFile adds a file source, decoded with the format registered for its
+// extension (see RegisterDecoder).
+func (l *Loader) AddFile(path string) *Loader {
+	l.sources = append(l.sources, loaderSource{kind: sourceFile, file: path})
+	return l
+}
+
+// AddEnv adds an environment source; variables must carry the given
+// prefix, as with EnvPrefix.
+func (l *Loader) AddEnv(prefix string) *Loader {
+	l.sources = append(l.sources, loaderSource{kind: sourceEnv, envPrefix: prefix})
+	return l
+}
+
+// AddFlags adds a flag.FlagSet source. Only flags actually set on the
+// command line are applied (via fs.Visit, not fs.VisitAll), so unset
+// flags don't clobber values from earlier sources with their zero value.
+// Flag names are matched the same way env paths are: dashes are treated
+// as the segment separator, so -app-name sets AppName.
+func (l *Loader) AddFlags(fs *flag.FlagSet) *Loader {
+	l.sources = append(l.sources, loaderSource{kind: sourceFlags, flags: fs})
+	return l
+}
+
+// Load decodes each source into a fresh instance of obj's type, in the
+// order the sources were added, and merges each one into obj field by
+// field, consulting the fieldMask each decode step produces: only a
+// field the source's mask marks as set is overwritten. This lets a later
+// source override an earlier one's field with its zero value (e.g. an
+// overlay turning Enabled off). A file in a custom-registered format
+// produces no mask (see loadFileMasked), so every field of its layer is
+// copied, zero values included. Once every source has been merged in,
+// Load applies default:"..." tags and checks required:"true" tags, the
+// same as LoadAndSync.
+func (l *Loader) Load(obj interface{}) error {
+	if err := checkConfigObj(obj); err != nil {
+		return err
+	}
+
+	dst := reflect.ValueOf(obj).Elem()
+
+	for _, src := range l.sources {
+		layer := reflect.New(dst.Type())
+		var mask fieldMask
+
+		switch src.kind {
+		case sourceFile:
+			m, err := loadFileMasked(src.file, layer.Interface())
+			if err != nil {
+				return fmt.Errorf("config: loader: %s: %w", src.file, err)
+			}
+			mask = m
+
+		case sourceEnv:
+			m, err := syncEnvWithPrefix(layer.Interface(), src.envPrefix)
+			if err != nil {
+				return fmt.Errorf("config: loader: env %s: %w", src.envPrefix, err)
+			}
+			mask = m
+
+		case sourceFlags:
+			m, err := applyFlags(layer.Elem(), src.flags)
+			if err != nil {
+				return fmt.Errorf("config: loader: flags: %w", err)
+			}
+			mask = m
+		}
+
+		mergeInto(dst, layer.Elem(), mask, nil)
+	}
+
+	return applyDefaultsAndRequired(obj)
+}
+
+// loadFileMasked decodes path into obj with the decoder registered for
+// its extension, as LoadConfig does, but without LoadConfig's fallback
+// to env/config-tagged variables. It additionally reports which fields the file actually contained - built
+// in formats (.json, .yaml/.yml, .toml, .env) report this precisely; any
+// other, custom-registered format has no generic presence decoder, so
+// every field obj ends up with is treated as set.
+func loadFileMasked(path string, obj interface{}) (fieldMask, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".env" {
+		return dotenvDecodeMasked(data, obj)
+	}
+
+	if err := LoadConfigFromReader(bytes.NewReader(data), ext, obj); err != nil {
+		return nil, err
+	}
+
+	generic, ok := genericDecoders[ext]
+	if !ok {
+		return nil, nil // no presence info available; caller merges the whole layer
+	}
+
+	value, err := generic(data)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := newFieldMask()
+	buildFileMask(reflect.TypeOf(obj).Elem(), value, nil, mask)
+	return mask, nil
+}
+
+// buildFileMask walks t alongside the generic map decoded from the same
+// bytes, recording a field as set in mask whenever its name (matched
+// case insensitively, since this package has no json/yaml tag
+// convention) is present as a key.
+func buildFileMask(t reflect.Type, generic interface{}, trail []string, mask fieldMask) {
+	m, ok := generic.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		value, ok := lookupKeyFold(m, field.Name)
+		if !ok {
+			continue
+		}
+
+		path := appendTrail(trail, field.Name)
+		mask.add(path)
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.Struct:
+			buildFileMask(ft, value, path, mask)
+		case reflect.Map:
+			buildFileMapMask(ft, value, path, mask)
+		}
+	}
+}
+
+// buildFileMapMask records each key of the generic map decoded for a
+// map-typed field (of type t) as set, so mergeInto can tell which keys a
+// later layer actually supplied apart from one it simply didn't mention.
+// Where the map's values are structs or maps, the keys present inside
+// each value are recorded too, since mergeInto recurses into those.
+func buildFileMapMask(t reflect.Type, generic interface{}, trail []string, mask fieldMask) {
+	m, ok := generic.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	et := t.Elem()
+	for k, v := range m {
+		path := appendTrail(trail, k)
+		mask.add(path)
+		switch et.Kind() {
+		case reflect.Struct:
+			buildFileMask(et, v, path, mask)
+		case reflect.Map:
+			buildFileMapMask(et, v, path, mask)
+		}
+	}
+}
+
+func lookupKeyFold(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// mergeInto copies src into dst field by field, recursing into structs,
+// pointers and maps, but only where mask says the field (or, for a map,
+// the individual key) was actually set by its source - a nil mask means
+// the source has no presence information (see loadFileMasked), so the
+// whole value it produced is copied as-is.
+func mergeInto(dst, src reflect.Value, mask fieldMask, trail []string) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		t := dst.Type()
+		for i := 0; i < dst.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			mergeInto(dst.Field(i), src.Field(i), mask, appendTrail(trail, t.Field(i).Name))
+		}
+
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		mergeInto(dst.Elem(), src.Elem(), mask, trail)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		if mask == nil {
+			dst.Set(src)
+			return
+		}
+
+		elemKind := dst.Type().Elem().Kind()
+		for _, key := range src.MapKeys() {
+			keyTrail := appendTrail(trail, fmt.Sprint(key.Interface()))
+			if !mask.has(keyTrail) {
+				continue
+			}
+
+			srcElem := src.MapIndex(key)
+			if elemKind != reflect.Struct && elemKind != reflect.Map {
+				if dst.IsNil() {
+					dst.Set(reflect.MakeMap(dst.Type()))
+				}
+				dst.SetMapIndex(key, srcElem)
+				continue
+			}
+
+			dstElem := reflect.New(dst.Type().Elem()).Elem()
+			if !dst.IsNil() {
+				if existing := dst.MapIndex(key); existing.IsValid() {
+					dstElem.Set(existing)
+				}
+			}
+			mergeInto(dstElem, srcElem, mask, keyTrail)
+
+			if dst.IsNil() {
+				dst.Set(reflect.MakeMap(dst.Type()))
+			}
+			dst.SetMapIndex(key, dstElem)
+		}
+
+	default:
+		if mask == nil || mask.has(trail) {
+			dst.Set(src)
+		}
+	}
+}
+
+// applyFlags resolves every flag that was set on the command line into v
+// via the same path-resolution used for environment variables, and
+// returns a fieldMask of the fields it touched.
+func applyFlags(v reflect.Value, fs *flag.FlagSet) (fieldMask, error) {
+	mask := newFieldMask()
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		path := strings.Split(strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_")), "_")
+		err = resolveEnvPath(v, path, f.Value.String(), mask, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mask, nil
+}
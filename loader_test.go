@@ -0,0 +1,142 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type loaderTestConfig struct {
+	AppName string
+	Port    int
+	Enabled bool
+	Tags    map[string]string
+}
+
+func writeTempFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "config-loader-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoaderOverlayOverridesZeroValue(t *testing.T) {
+	base := writeTempFile(t, "base.json", `{"AppName":"billing","Port":8080,"Enabled":true}`)
+	overlay := writeTempFile(t, "overlay.json", `{"Enabled":false}`)
+
+	var cfg loaderTestConfig
+	err := NewLoader().AddFile(base).AddFile(overlay).Load(&cfg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "billing")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want %d", cfg.Port, 8080)
+	}
+	if cfg.Enabled {
+		t.Errorf("Enabled = true, want false - an overlay explicitly setting a field to its zero value must win")
+	}
+}
+
+func TestLoaderEnvOverridesFile(t *testing.T) {
+	base := writeTempFile(t, "base.json", `{"AppName":"billing","Enabled":true}`)
+
+	os.Setenv("CONFIG_ENABLED", "false")
+	defer os.Unsetenv("CONFIG_ENABLED")
+
+	var cfg loaderTestConfig
+	err := NewLoader().AddFile(base).AddEnv("CONFIG").Load(&cfg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Enabled {
+		t.Errorf("Enabled = true, want false - CONFIG_ENABLED=false must override the file's true")
+	}
+	if cfg.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q - env source must not touch fields it didn't set", cfg.AppName, "billing")
+	}
+}
+
+func TestLoaderFlagsOverrideFile(t *testing.T) {
+	base := writeTempFile(t, "base.json", `{"AppName":"billing","Enabled":true}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("enabled", true, "")
+	if err := fs.Parse([]string{"-enabled=false"}); err != nil {
+		t.Fatalf("fs.Parse: %v", err)
+	}
+
+	var cfg loaderTestConfig
+	err := NewLoader().AddFile(base).AddFlags(fs).Load(&cfg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Enabled {
+		t.Errorf("Enabled = true, want false - an explicitly set flag must override the file's true")
+	}
+}
+
+func TestLoaderUnsetFlagsDoNotOverride(t *testing.T) {
+	base := writeTempFile(t, "base.json", `{"AppName":"billing","Enabled":true}`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("enabled", false, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("fs.Parse: %v", err)
+	}
+
+	var cfg loaderTestConfig
+	err := NewLoader().AddFile(base).AddFlags(fs).Load(&cfg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Enabled {
+		t.Errorf("Enabled = false, want true - an unset flag must not override an earlier source")
+	}
+}
+
+func TestLoaderMergesMapsPerKeyNotWholesale(t *testing.T) {
+	base := writeTempFile(t, "base.json", `{"Tags":{"region":"us","team":"billing"}}`)
+	overlay := writeTempFile(t, "overlay.json", `{"Tags":{"region":"eu"}}`)
+
+	var cfg loaderTestConfig
+	err := NewLoader().AddFile(base).AddFile(overlay).Load(&cfg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := map[string]string{"region": "eu", "team": "billing"}
+	if len(cfg.Tags) != len(want) {
+		t.Fatalf("Tags = %+v, want %+v", cfg.Tags, want)
+	}
+	for k, v := range want {
+		if cfg.Tags[k] != v {
+			t.Errorf("Tags[%q] = %q, want %q", k, cfg.Tags[k], v)
+		}
+	}
+}
+
+func TestFieldMaskAddAncestors(t *testing.T) {
+	mask := newFieldMask()
+	mask.addAncestors([]string{"Tags", "env"})
+
+	if !mask.has([]string{"Tags"}) {
+		t.Error("mask does not contain the ancestor path [Tags]")
+	}
+	if !mask.has([]string{"Tags", "env"}) {
+		t.Error("mask does not contain the full path [Tags env]")
+	}
+}
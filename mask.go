@@ -0,0 +1,47 @@
+package config
+
+import "strings"
+
+// fieldMask records, as dotted Go field-name paths, which fields of a
+// decoded value were actually supplied by its source - as opposed to
+// merely sitting at their zero value - so Loader.Load can tell "this
+// source didn't touch the field" apart from "this source explicitly set
+// it to the zero value".
+type fieldMask map[string]struct{}
+
+func newFieldMask() fieldMask {
+	return fieldMask{}
+}
+
+func (m fieldMask) add(path []string) {
+	if m == nil {
+		return
+	}
+	m[strings.Join(path, ".")] = struct{}{}
+}
+
+// addAncestors marks path and every prefix of it as set. A leaf set deep
+// inside a map or interface{} value needs its ancestors marked too,
+// since mergeInto checks a map key's own path before recursing into its
+// value, and copies an interface{} value wholesale at the field level
+// that holds it.
+func (m fieldMask) addAncestors(path []string) {
+	for i := 1; i <= len(path); i++ {
+		m.add(path[:i])
+	}
+}
+
+func (m fieldMask) has(path []string) bool {
+	_, ok := m[strings.Join(path, ".")]
+	return ok
+}
+
+// appendTrail returns trail with name appended, without risking the
+// returned slice sharing (and later corrupting) a sibling call's
+// backing array.
+func appendTrail(trail []string, name string) []string {
+	next := make([]string, len(trail)+1)
+	copy(next, trail)
+	next[len(trail)] = name
+	return next
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// String returns a human-readable representation of obj suitable for
+// logging, with any field tagged secret:"true" redacted as "***". obj
+// must be a pointer to a struct, or a struct value. Secret fields are
+// redacted in obj itself and in nested structs and non-nil struct
+// pointers; a struct held in a slice, map or interface{} value is
+// printed with %v, without redaction.
+func String(obj interface{}) string {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	return redactValue(v)
+}
+
+// Sprint is an alias for String, for call sites that read more naturally
+// as fmt.Sprint.
+func Sprint(obj interface{}) string {
+	return String(obj)
+}
+
+func redactValue(v reflect.Value) string {
+	if v.Kind() != reflect.Struct {
+		return fmt.Sprintf("%v", v.Interface())
+	}
+
+	t := v.Type()
+	parts := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		var val string
+		switch {
+		case parseFieldTag(field).secret:
+			val = "***"
+		case fv.Kind() == reflect.Ptr && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct:
+			val = redactValue(fv.Elem())
+		case fv.Kind() == reflect.Struct:
+			val = redactValue(fv)
+		default:
+			val = fmt.Sprintf("%v", fv.Interface())
+		}
+
+		parts = append(parts, fmt.Sprintf("%s:%s", field.Name, val))
+	}
+
+	return fmt.Sprintf("%s{%s}", t.Name(), strings.Join(parts, " "))
+}
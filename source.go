@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Source fetches raw configuration bytes from somewhere other than a
+// plain local file, along with a format hint (a decoder extension such
+// as "json" or ".yaml") LoadFrom uses to pick a decoder from the format
+// registry - see RegisterDecoder. FileSource and HTTPSource implement it
+// here; github.com/sedind/config/consul and .../etcd provide Consul- and
+// etcd-backed implementations in their own modules, so that callers who
+// need neither don't pay for their dependencies.
+type Source interface {
+	Fetch(ctx context.Context) (data []byte, format string, err error)
+}
+
+// LoadFrom decodes configuration fetched from src into obj, picking a
+// decoder by the format hint src.Fetch returns.
+func LoadFrom(ctx context.Context, src Source, obj interface{}) error {
+	if err := checkConfigObj(obj); err != nil {
+		return err
+	}
+
+	data, format, err := src.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(format, ".") {
+		format = "." + format
+	}
+
+	decode, err := decoderFor(format)
+	if err != nil {
+		return err
+	}
+
+	return decode(data, obj)
+}
+
+// FileSource reads configuration from a local file, inferring the
+// format from its extension.
+type FileSource struct {
+	Path string
+}
+
+// Fetch implements Source.
+func (s FileSource) Fetch(ctx context.Context) ([]byte, string, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, filepath.Ext(s.Path), nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+)
+
+// HTTPSource fetches configuration from an HTTP(S) URL. It caches the
+// ETag of the last successful response and sends it as If-None-Match on
+// the next Fetch; a 304 response returns the previously cached body
+// instead of an empty one.
+type HTTPSource struct {
+	URL    string
+	Format string // decoder hint; defaults to filepath.Ext(URL) if empty
+	Client *http.Client
+
+	etag string
+	body []byte
+}
+
+// Fetch implements Source.
+func (s *HTTPSource) Fetch(ctx context.Context) ([]byte, string, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return s.body, s.format(), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("config: %s: unexpected status %s", s.URL, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.body = data
+	return data, s.format(), nil
+}
+
+func (s *HTTPSource) format() string {
+	if s.Format != "" {
+		return s.Format
+	}
+	return filepath.Ext(s.URL)
+}
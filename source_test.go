@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type sourceTestConfig struct {
+	AppName string
+}
+
+func TestHTTPSourceFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"AppName":"billing"}`))
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{URL: srv.URL + "/config.json"}
+	data, format, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if format != ".json" {
+		t.Errorf("format = %q, want %q", format, ".json")
+	}
+	if string(data) != `{"AppName":"billing"}` {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestHTTPSourceNotModifiedReturnsCachedBody(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"AppName":"billing"}`))
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{URL: srv.URL + "/config.json"}
+	if _, _, err := src.Fetch(context.Background()); err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+
+	data, _, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want %d", calls, 2)
+	}
+	if string(data) != `{"AppName":"billing"}` {
+		t.Errorf("data = %q, want the cached body from the first response", data)
+	}
+}
+
+func TestLoadFrom(t *testing.T) {
+	src := FileSource{Path: writeTempFile(t, "config.json", `{"AppName":"billing"}`)}
+
+	var cfg sourceTestConfig
+	if err := LoadFrom(context.Background(), src, &cfg); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if cfg.AppName != "billing" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "billing")
+	}
+}
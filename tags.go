@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// fieldTag holds the parsed `config`/`env`/`default`/`required`/`secret`
+// struct tags for a single field.
+type fieldTag struct {
+	name     string // explicit env var name, from the config or env tag
+	def      string // default value, from the default tag
+	required bool
+	secret   bool
+}
+
+func parseFieldTag(field reflect.StructField) fieldTag {
+	name := field.Tag.Get("config")
+	if name == "" {
+		name = field.Tag.Get("env")
+	}
+
+	return fieldTag{
+		name:     name,
+		def:      field.Tag.Get("default"),
+		required: field.Tag.Get("required") == "true",
+		secret:   field.Tag.Get("secret") == "true",
+	}
+}
+
+// applyTagEnv walks v recursively and, for every field still at its zero
+// value and carrying an explicit config/env tag name, fills it in from
+// that literal environment variable when set. It is how a field can opt
+// out of the derived APP_NAME-style path entirely, e.g.
+// `env:"DATABASE_URL"`. It only fills in what's still unset, so it never
+// clobbers a value LoadConfig already read from the file or SyncEnv
+// already resolved from the derived path.
+//
+// trail is the dotted Go field-name path walked so far; every field it
+// fills in is recorded in mask, which may be nil if the caller doesn't
+// need one.
+func applyTagEnv(v reflect.Value, mask fieldMask, trail []string) error {
+	return applyTagEnvFrom(v, os.LookupEnv, mask, trail)
+}
+
+// applyTagEnvFrom is applyTagEnv with the variable lookup abstracted
+// behind lookup, so callers that must not read the live process
+// environment - dotenvDecodeMasked, notably - can resolve tag names
+// against a different source instead.
+func applyTagEnvFrom(v reflect.Value, lookup func(string) (string, bool), mask fieldMask, trail []string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		path := appendTrail(trail, field.Name)
+
+		if tag := parseFieldTag(field); tag.name != "" && isZero(fv) {
+			if value, ok := lookup(tag.name); ok {
+				if err := decodeValue(value, fv); err != nil {
+					return fmt.Errorf("config: field %s: env %s: %w", field.Name, tag.name, err)
+				}
+				mask.add(path)
+			}
+		}
+
+		if err := applyTagEnvFrom(fv, lookup, mask, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyDefaultsAndRequired walks obj recursively. It first sets any
+// still-zero field carrying a `default:"..."` tag, then - once defaults
+// have had a chance to apply - collects every field tagged
+// required:"true" that is still at its zero value. It returns a
+// MultiError listing every missing required field, or nil if none are
+// missing.
+//
+// It is meant to run after both LoadConfig and SyncEnv, so it sees the
+// fully merged configuration.
+func applyDefaultsAndRequired(obj interface{}) error {
+	if err := checkConfigObj(obj); err != nil {
+		return err
+	}
+
+	var missing MultiError
+	walkTags(reflect.ValueOf(obj).Elem(), "", &missing)
+	if len(missing) == 0 {
+		return nil
+	}
+	return missing
+}
+
+func walkTags(v reflect.Value, path string, missing *MultiError) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		tag := parseFieldTag(field)
+		name := field.Name
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if tag.def != "" && isZero(fv) {
+			if err := decodeValue(tag.def, fv); err != nil {
+				missing.append(fmt.Errorf("config: field %s: invalid default %q: %w", name, tag.def, err))
+				continue
+			}
+		}
+
+		walkTags(fv, name, missing)
+
+		if tag.required && isZero(fv) {
+			missing.append(fmt.Errorf("config: field %s is required", name))
+		}
+	}
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
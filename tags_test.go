@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+type tagsTestConfig struct {
+	Name     string `default:"anon"`
+	Port     int    `default:"8080" required:"true"`
+	APIKey   string `env:"API_KEY" secret:"true"`
+	Required string `required:"true"`
+}
+
+func TestApplyDefaultsAndRequired(t *testing.T) {
+	cfg := tagsTestConfig{Required: "set"}
+	if err := applyDefaultsAndRequired(&cfg); err != nil {
+		t.Fatalf("applyDefaultsAndRequired: %v", err)
+	}
+	if cfg.Name != "anon" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "anon")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want %d", cfg.Port, 8080)
+	}
+}
+
+func TestApplyDefaultsAndRequiredMissing(t *testing.T) {
+	var cfg tagsTestConfig
+	err := applyDefaultsAndRequired(&cfg)
+	if err == nil {
+		t.Fatal("applyDefaultsAndRequired: want error for missing Required field, got nil")
+	}
+	if _, ok := err.(MultiError); !ok {
+		t.Fatalf("applyDefaultsAndRequired: want MultiError, got %T", err)
+	}
+}
+
+func TestApplyDefaultsDoesNotOverrideSetValue(t *testing.T) {
+	cfg := tagsTestConfig{Name: "billing", Required: "set"}
+	if err := applyDefaultsAndRequired(&cfg); err != nil {
+		t.Fatalf("applyDefaultsAndRequired: %v", err)
+	}
+	if cfg.Name != "billing" {
+		t.Errorf("Name = %q, want %q - default must not override an already-set field", cfg.Name, "billing")
+	}
+}
+
+func TestApplyTagEnvFallsBackOnlyWhenUnset(t *testing.T) {
+	os.Setenv("API_KEY", "from-env")
+	defer os.Unsetenv("API_KEY")
+
+	cfg := tagsTestConfig{APIKey: "from-file"}
+	if err := applyTagEnv(reflect.ValueOf(&cfg).Elem(), nil, nil); err != nil {
+		t.Fatalf("applyTagEnv: %v", err)
+	}
+	if cfg.APIKey != "from-file" {
+		t.Errorf("APIKey = %q, want %q - env tag must not clobber a value already set", cfg.APIKey, "from-file")
+	}
+
+	var empty tagsTestConfig
+	if err := applyTagEnv(reflect.ValueOf(&empty).Elem(), nil, nil); err != nil {
+		t.Fatalf("applyTagEnv: %v", err)
+	}
+	if empty.APIKey != "from-env" {
+		t.Errorf("APIKey = %q, want %q - env tag must fill an unset field", empty.APIKey, "from-env")
+	}
+}
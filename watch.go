@@ -0,0 +1,148 @@
+package config
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceWindow coalesces the burst of filesystem events a single save
+// can trigger (some editors write, chmod and rename in quick succession)
+// into one reload.
+const debounceWindow = 100 * time.Millisecond
+
+// Watcher holds the most recently loaded value of a watched
+// configuration file. It is safe for concurrent use.
+type Watcher struct {
+	mu      sync.RWMutex
+	current interface{}
+
+	fsw  *fsnotify.Watcher
+	done chan struct{}
+}
+
+// Get returns the most recently loaded configuration value.
+func (w *Watcher) Get() interface{} {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Close stops the underlying filesystem watcher.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// Watch loads obj from path and then watches it for further changes. On
+// every write, create or rename-into-place (the file's containing
+// directory is watched rather than the file itself, so editors that save
+// via rename-swap are handled; other events are ignored), it
+// decodes a fresh instance of obj's type, runs onChange with deep copies
+// of the previous and new values, and only on success swaps the new
+// value in behind Get. A reload that fails to decode, or whose onChange
+// returns an error, is discarded and the previously loaded value is
+// kept.
+//
+// Events arriving within debounceWindow of each other are coalesced into
+// a single reload.
+//
+// The returned io.Closer stops the watcher.
+func Watch(path string, obj interface{}, onChange func(old, new interface{}) error) (io.Closer, error) {
+	if err := checkConfigObj(obj); err != nil {
+		return nil, err
+	}
+
+	if err := LoadConfig(path, obj); err != nil {
+		return nil, err
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		current: obj,
+		fsw:     fsw,
+		done:    make(chan struct{}),
+	}
+
+	go w.run(path, onChange)
+
+	return w, nil
+}
+
+func (w *Watcher) run(path string, onChange func(old, new interface{}) error) {
+	var debounce *time.Timer
+	reload := make(chan struct{}, 1)
+
+	for {
+		select {
+		case <-w.done:
+			if debounce != nil {
+				debounce.Stop()
+			}
+			return
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != filepath.Clean(path) {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(debounceWindow, func() {
+					select {
+					case reload <- struct{}{}:
+					default:
+					}
+				})
+			} else {
+				debounce.Reset(debounceWindow)
+			}
+
+		case <-reload:
+			w.reload(path, onChange)
+
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) reload(path string, onChange func(old, new interface{}) error) {
+	w.mu.RLock()
+	old := w.current
+	w.mu.RUnlock()
+
+	next := reflect.New(reflect.TypeOf(old).Elem()).Interface()
+	if err := LoadConfig(path, next); err != nil {
+		return
+	}
+
+	if onChange != nil {
+		if err := onChange(deepCopy(old), deepCopy(next)); err != nil {
+			return
+		}
+	}
+
+	w.mu.Lock()
+	w.current = next
+	w.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type watchTestConfig struct {
+	Port int
+}
+
+func TestWatchReloadsOnChangeWithDeepCopies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-watch-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"Port":8080}`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var cfg watchTestConfig
+	seen := make(chan [2]int, 1)
+	closer, err := Watch(path, &cfg, func(old, next interface{}) error {
+		o := old.(*watchTestConfig)
+		n := next.(*watchTestConfig)
+		n.Port = -1 // mutating what onChange received must not reach Get()
+		seen <- [2]int{o.Port, n.Port}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer closer.Close()
+
+	if err := ioutil.WriteFile(path, []byte(`{"Port":9090}`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	select {
+	case got := <-seen:
+		if got[0] != 8080 {
+			t.Errorf("old.Port = %d, want %d", got[0], 8080)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called after the file changed")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		w := closer.(*Watcher)
+		if cur := w.Get().(*watchTestConfig); cur.Port == 9090 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Get() never reflected the reloaded value")
+}